@@ -0,0 +1,173 @@
+// Command summary-issues is a GitHub Action (and friends) that rolls up
+// issues matching a label into a "summary" issue that tracks them.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/sourcegraph/summary-issues/pkg/forge"
+	"github.com/sourcegraph/summary-issues/pkg/summary"
+)
+
+func main() {
+	if err := testableMain(os.Stdout, os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func testableMain(stdout io.Writer, args []string) error {
+	opts, e, err := githubActionOptions(args)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("event=%q\n", e.Name)
+	switch e.Name {
+	case "issues":
+		if e.Issue.Labels.Contains("summary") && isany(e.Action, "edited", "labeled", "unlabeled", "opened") {
+			if err := summary.UpdateSummaryIssue(opts, summary.Issue{
+				ID:     e.Issue.ID,
+				URL:    e.Issue.URL,
+				Title:  e.Issue.Title,
+				Labels: e.Issue.Labels,
+			}); err != nil {
+				return err
+			}
+		}
+
+		switch e.Action {
+		case "labeled", "unlabeled":
+			if e.Label.Name == "summary" {
+				return nil
+			}
+			return summary.UpdateSummaryIssues(opts, summary.Labels{*e.Label})
+		case "opened":
+			labels := e.Issue.Labels.NonSummary()
+			if len(labels) == 0 {
+				return nil
+			}
+			return summary.UpdateSummaryIssues(opts, labels)
+		}
+	case "issue_comment":
+		labels := e.Issue.Labels.NonSummary()
+		if len(labels) == 0 {
+			return nil
+		}
+		// TODO: could fail fast using knowledge of comment and regex filter
+		return summary.UpdateSummaryIssues(opts, labels)
+	case "schedule":
+		return summary.RunScheduledDigest(opts)
+	default:
+		fmt.Printf("nothing to update\n")
+	}
+	return nil
+}
+
+func githubActionOptions(args []string) (*summary.Options, *event, error) {
+	repo := os.Getenv("GITHUB_REPOSITORY")
+	i := strings.IndexRune(repo, '/')
+	if i < 1 {
+		return nil, nil, fmt.Errorf("invalid value for GITHUB_REPOSITORY env var: %q", repo)
+	}
+
+	user := repo[:i]
+
+	path := os.Getenv("GITHUB_EVENT_PATH")
+	if path == "" {
+		return nil, nil, fmt.Errorf("env var GITHUB_EVENT_PATH not set")
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to read GitHub event json %s: %s", path, err)
+	}
+
+	name := os.Getenv("GITHUB_EVENT_NAME")
+	if name == "" {
+		return nil, nil, fmt.Errorf("env var GITHUB_EVENT_NAME not set")
+	}
+	e := &event{
+		Name: name,
+	}
+
+	if err := json.Unmarshal(data, e); err != nil {
+		return nil, nil, fmt.Errorf("unable to decode GitHub event: %s\n%s", err, string(data))
+	}
+
+	opts := &summary.Options{
+		User: user,
+	}
+
+	flags := flag.NewFlagSet("summary", flag.ContinueOnError)
+	re := flags.String("summaryCommentRegex", "", "The newest comment on an issue that matches this regular expression is used in the summary. If not provided, the most recent comment is always used.")
+	forgeType := flags.String("forge", os.Getenv("FORGE_TYPE"), "The forge to talk to: github (default), gitlab, gitea, or gerrit. Can also be set via the FORGE_TYPE env var.")
+	template := flags.String("template", "", "Path to a text/template file used to render summary issue bodies. If not provided, a built-in template is used.")
+	notifyChildren := flags.Bool("notifyChildren", false, "If set, post or update a comment on each child issue linking back to the summary it was rolled up into.")
+
+	if err := flags.Parse(args); err != nil {
+		return nil, nil, err
+	}
+	opts.SummaryCommentRegex, err = regexp.Compile(*re)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	opts.Forge, err = forge.New(*forgeType, user)
+	if err != nil {
+		return nil, nil, err
+	}
+	opts.TemplatePath = *template
+	opts.NotifyChildren = *notifyChildren
+
+	return opts, e, nil
+}
+
+type event struct {
+	Name    string
+	Changes struct {
+		Body struct {
+			From string `json:"from"`
+		} `json:"body"`
+	} `json:"changes"`
+	Action  string         `json:"action"`
+	Issue   *restIssue     `json:"issue"`
+	Label   *summary.Label `json:"label"`
+	Comment *restComment   `json:"comment"`
+}
+
+type restIssue struct {
+	ID     string         `json:"node_id"`
+	URL    string         `json:"html_url"`
+	Title  string         `json:"title"`
+	Body   string         `json:"body"`
+	Author restActor      `json:"user"`
+	Labels summary.Labels `json:"labels"`
+}
+
+type restComment struct {
+	Author    restActor `json:"user"`
+	Body      string    `json:"body"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type restActor struct {
+	Login string `json:"login"`
+}
+
+func isany(s string, v ...string) bool {
+	for _, x := range v {
+		if s == x {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func withEnv(t *testing.T, key, value string) {
+	t.Helper()
+	old, had := os.LookupEnv(key)
+	if err := os.Setenv(key, value); err != nil {
+		t.Fatalf("os.Setenv(%q, %q): %v", key, value, err)
+	}
+	t.Cleanup(func() {
+		if had {
+			os.Setenv(key, old)
+		} else {
+			os.Unsetenv(key)
+		}
+	})
+}
+
+// fakeGitHub serves just enough of GitHub's GraphQL API for testableMain to
+// run end-to-end: a search that returns one summary issue for "label:summary"
+// queries and one child issue for any other query, and an updateIssue
+// mutation that records the body it was asked to write.
+func fakeGitHub(t *testing.T) (srv *httptest.Server, updatedBody *string) {
+	t.Helper()
+	body := new(string)
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reqbody, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("fakeGitHub: error reading request: %v", err)
+		}
+		var req struct {
+			Query     string                 `json:"query"`
+			Variables map[string]interface{} `json:"variables"`
+		}
+		if err := json.Unmarshal(reqbody, &req); err != nil {
+			t.Fatalf("fakeGitHub: error decoding request: %v", err)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.Contains(req.Query, "mutation UpdateIssue"):
+			*body = req.Variables["body"].(string)
+			json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{}})
+		case strings.Contains(req.Query, "search("):
+			summaryIssue := map[string]interface{}{
+				"id":        "summary-issue-1",
+				"url":       "https://github.com/acme/widgets/issues/2",
+				"title":     "Team A tracker",
+				"body":      "",
+				"state":     "OPEN",
+				"createdAt": "2024-01-01T00:00:00Z",
+				"author":    map[string]interface{}{"login": "alice"},
+				"labels": map[string]interface{}{
+					"nodes": []map[string]interface{}{{"name": "summary"}, {"name": "team-a"}},
+				},
+				"comments": map[string]interface{}{"nodes": []map[string]interface{}{}},
+			}
+			childIssue := map[string]interface{}{
+				"id":        "MDU6SXNzdWUx",
+				"url":       "https://github.com/acme/widgets/issues/1",
+				"title":     "Sprockets are loose",
+				"body":      "details",
+				"state":     "OPEN",
+				"createdAt": "2024-01-02T00:00:00Z",
+				"author":    map[string]interface{}{"login": "alice"},
+				"labels": map[string]interface{}{
+					"nodes": []map[string]interface{}{{"name": "team-a"}},
+				},
+				"comments": map[string]interface{}{"nodes": []map[string]interface{}{}},
+			}
+
+			// Mirror what a real search would do: "label:summary" narrows
+			// to the summary issue itself, anything else is a search for
+			// the child issues it rolls up.
+			nodes := []map[string]interface{}{childIssue}
+			if strings.Contains(req.Variables["query"].(string), "label:summary") {
+				nodes = []map[string]interface{}{summaryIssue}
+			}
+
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{
+					"search": map[string]interface{}{"nodes": nodes},
+				},
+			})
+		default:
+			t.Fatalf("fakeGitHub: unexpected query: %s", req.Query)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, body
+}
+
+func TestTestableMainIssueLabeled(t *testing.T) {
+	srv, updatedBody := fakeGitHub(t)
+
+	withEnv(t, "GITHUB_REPOSITORY", "acme/widgets")
+	withEnv(t, "GITHUB_EVENT_NAME", "issues")
+	withEnv(t, "GITHUB_EVENT_PATH", filepath.Join("testdata", "issue_labeled.json"))
+	withEnv(t, "GITHUB_GRAPHQL_URL", srv.URL)
+	withEnv(t, "GITHUB_SERVER_URL", "https://github.com")
+	withEnv(t, "GITHUB_TOKEN", "fake-token")
+	withEnv(t, "RUNNER_TOOL_CACHE", t.TempDir())
+	withEnv(t, "FORGE_TYPE", "")
+
+	if err := testableMain(&bytes.Buffer{}, nil); err != nil {
+		t.Fatalf("testableMain() error = %v", err)
+	}
+
+	if *updatedBody == "" {
+		t.Fatal("testableMain() never updated the summary issue body")
+	}
+	if !strings.Contains(*updatedBody, "Sprockets are loose") {
+		t.Errorf("updated summary body = %q, want it to mention the child issue", *updatedBody)
+	}
+}
@@ -0,0 +1,265 @@
+package forge
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// graphqlCacheTTL is how long a cached GraphQL response is served without
+// revalidating against GitHub. It's short because issue state changes
+// frequently, but still collapses the bursts of near-identical searchIssues
+// calls a single webhook fan-out triggers.
+const graphqlCacheTTL = 30 * time.Second
+
+var httpClientOnce struct {
+	sync.Once
+	client *http.Client
+}
+
+// newHTTPClient returns the process-wide http.Client used for all forge API
+// calls: a disk-backed cache that revalidates with conditional requests
+// instead of re-fetching unchanged responses, wrapping a rate limiter that
+// smooths the bursts (e.g. many labels changing at once) of requests that
+// actually go out over the network. It's a singleton so the rate
+// limiter's token bucket is actually shared across the many calls one event
+// can trigger.
+func newHTTPClient() *http.Client {
+	httpClientOnce.Do(func() {
+		httpClientOnce.client = &http.Client{
+			Transport: &cachingRoundTripper{
+				cache: newHTTPCache(),
+				ttl:   graphqlCacheTTL,
+				next: &rateLimitedRoundTripper{
+					next:    http.DefaultTransport,
+					limiter: rate.NewLimiter(rate.Limit(envFloat("SUMMARY_ISSUES_QPS", 2)), envInt("SUMMARY_ISSUES_BURST", 5)),
+				},
+			},
+		}
+	})
+	return httpClientOnce.client
+}
+
+// rateLimitedRoundTripper throttles outgoing requests to a configurable
+// QPS/burst, so a flurry of label changes doesn't blow through the forge's
+// rate limit.
+type rateLimitedRoundTripper struct {
+	next    http.RoundTripper
+	limiter *rate.Limiter
+}
+
+func (t *rateLimitedRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.limiter.Wait(req.Context()); err != nil {
+		return nil, fmt.Errorf("rate limiter: %w", err)
+	}
+	return t.next.RoundTrip(req)
+}
+
+// cachingRoundTripper caches responses on disk keyed by the request method,
+// URL, and body, and revalidates stale entries with If-None-Match /
+// If-Modified-Since instead of blindly re-fetching. Responses served from
+// cache without revalidation carry a synthetic X-From-Cache header so
+// callers can log hits.
+type cachingRoundTripper struct {
+	next  http.RoundTripper
+	cache *httpCache
+	ttl   time.Duration
+}
+
+func (t *cachingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	key, body, err := cacheKey(req)
+	if err != nil {
+		return nil, err
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	if !isCacheableRequest(req.Method, body) {
+		return t.next.RoundTrip(req)
+	}
+
+	entry, ok := t.cache.Load(key)
+	if ok && time.Since(entry.StoredAt) < t.ttl {
+		return entry.response(req, "hit"), nil
+	}
+
+	if ok {
+		if entry.ETag != "" {
+			req.Header.Set("If-None-Match", entry.ETag)
+		}
+		if entry.LastModified != "" {
+			req.Header.Set("If-Modified-Since", entry.LastModified)
+		}
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if ok && resp.StatusCode == http.StatusNotModified {
+		resp.Body.Close()
+		entry.StoredAt = time.Now()
+		t.cache.Store(key, entry)
+		return entry.response(req, "revalidated"), nil
+	}
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	fresh := &cachedResponse{
+		StatusCode:   resp.StatusCode,
+		Header:       resp.Header.Clone(),
+		Body:         respBody,
+		StoredAt:     time.Now(),
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	}
+	if resp.StatusCode == http.StatusOK {
+		t.cache.Store(key, fresh)
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+	return resp, nil
+}
+
+// isCacheableRequest reports whether a request is safe to serve from (or
+// store in) the cache: GET requests are always reads. POST requests are
+// cacheable only when they're a GraphQL *query* -- GitHub's GraphQL reads
+// and writes both go through POST to the same endpoint, and a cached
+// "success" response standing in for a real mutation would silently drop
+// writes like AddComment. Every other POST/PUT/etc. (GitLab/Gerrit writes,
+// GraphQL mutations) is never cached.
+func isCacheableRequest(method string, body []byte) bool {
+	if method == http.MethodGet {
+		return true
+	}
+	if method != http.MethodPost {
+		return false
+	}
+	var gql struct {
+		Query string `json:"query"`
+	}
+	if err := json.Unmarshal(body, &gql); err != nil {
+		return false
+	}
+	return strings.HasPrefix(strings.TrimSpace(gql.Query), "query")
+}
+
+// cacheKey hashes the request method, URL, and body (GraphQL queries are
+// POSTs, so the body -- query+variables -- has to be part of the key) and
+// also returns the body bytes so the caller can restore req.Body after
+// reading it.
+func cacheKey(req *http.Request) (string, []byte, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(req.Body)
+		if err != nil {
+			return "", nil, fmt.Errorf("error reading request body for cache key: %w", err)
+		}
+	}
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n%s\n", req.Method, req.URL.String())
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil)), body, nil
+}
+
+// cachedResponse is a cache entry serialized to disk.
+type cachedResponse struct {
+	StatusCode   int
+	Header       http.Header
+	Body         []byte
+	StoredAt     time.Time
+	ETag         string
+	LastModified string
+}
+
+// response reconstructs an *http.Response from the cache entry, tagged with
+// the synthetic X-From-Cache header describing why it was served (hit vs.
+// revalidated).
+func (c *cachedResponse) response(req *http.Request, fromCache string) *http.Response {
+	header := c.Header.Clone()
+	header.Set("X-From-Cache", fromCache)
+	return &http.Response{
+		StatusCode: c.StatusCode,
+		Status:     http.StatusText(c.StatusCode),
+		Header:     header,
+		Body:       ioutil.NopCloser(bytes.NewReader(c.Body)),
+		Request:    req,
+	}
+}
+
+// httpCache is a disk-backed store for cachedResponse entries, rooted under
+// $RUNNER_TOOL_CACHE (the GitHub Actions runner's persistent tool cache
+// directory) so hits survive across job steps, falling back to the OS temp
+// dir when running outside an Action.
+type httpCache struct {
+	dir string
+}
+
+func newHTTPCache() *httpCache {
+	dir := os.Getenv("RUNNER_TOOL_CACHE")
+	if dir == "" {
+		dir = os.TempDir()
+	}
+	dir = filepath.Join(dir, "summary-issues-http-cache")
+	_ = os.MkdirAll(dir, 0o755)
+	return &httpCache{dir: dir}
+}
+
+func (c *httpCache) path(key string) string {
+	return filepath.Join(c.dir, key+".json")
+}
+
+func (c *httpCache) Load(key string) (*cachedResponse, bool) {
+	data, err := ioutil.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+	var entry cachedResponse
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, false
+	}
+	return &entry, true
+}
+
+func (c *httpCache) Store(key string, entry *cachedResponse) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	_ = ioutil.WriteFile(c.path(key), data, 0o644)
+}
+
+// envFloat and envInt read tunables for the rate limiter from the
+// environment, falling back to def when unset or unparseable.
+func envFloat(name string, def float64) float64 {
+	v, err := strconv.ParseFloat(os.Getenv(name), 64)
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+func envInt(name string, def int) int {
+	v, err := strconv.Atoi(os.Getenv(name))
+	if err != nil {
+		return def
+	}
+	return v
+}
@@ -0,0 +1,163 @@
+package forge
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestIsCacheableRequest(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		body   string
+		want   bool
+	}{
+		{
+			name:   "GET is always cacheable",
+			method: "GET",
+			body:   "",
+			want:   true,
+		},
+		{
+			name:   "GraphQL query is cacheable",
+			method: "POST",
+			body:   `{"query":"\n\t\t\tquery SearchIssues ($query: String!) { search(type: ISSUE) { nodes { id } } }"}`,
+			want:   true,
+		},
+		{
+			name:   "GraphQL mutation is never cacheable",
+			method: "POST",
+			body:   `{"query":"\n\t\t\tmutation AddComment ($id: String!, $body: String!) { addComment(input: {}) { clientMutationId } }"}`,
+			want:   false,
+		},
+		{
+			name:   "non-GraphQL POST (e.g. GitLab/Gerrit writes) is never cacheable",
+			method: "POST",
+			body:   `{"body":"hello"}`,
+			want:   false,
+		},
+		{
+			name:   "PUT is never cacheable",
+			method: "PUT",
+			body:   `{"description":"hello"}`,
+			want:   false,
+		},
+		{
+			name:   "unparseable body defaults to not cacheable",
+			method: "POST",
+			body:   `not json`,
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isCacheableRequest(tt.method, []byte(tt.body)); got != tt.want {
+				t.Errorf("isCacheableRequest(%q, %q) = %v, want %v", tt.method, tt.body, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestCachingRoundTripperServesHitWithinTTL verifies the feature chunk0-3
+// actually asked for: a second identical cacheable request within the TTL is
+// served from disk, never reaching the server, and is tagged X-From-Cache.
+func TestCachingRoundTripperServesHitWithinTTL(t *testing.T) {
+	withEnv(t, "RUNNER_TOOL_CACHE", t.TempDir())
+
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte("hello"))
+	}))
+	t.Cleanup(srv.Close)
+
+	rt := &cachingRoundTripper{next: http.DefaultTransport, cache: newHTTPCache(), ttl: time.Minute}
+
+	req1, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	if err != nil {
+		t.Fatalf("http.NewRequest() error = %v", err)
+	}
+	resp1, err := rt.RoundTrip(req1)
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	resp1.Body.Close()
+	if got := resp1.Header.Get("X-From-Cache"); got != "" {
+		t.Errorf("first response X-From-Cache = %q, want empty (not served from cache)", got)
+	}
+
+	req2, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	if err != nil {
+		t.Fatalf("http.NewRequest() error = %v", err)
+	}
+	resp2, err := rt.RoundTrip(req2)
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	resp2.Body.Close()
+	if got := resp2.Header.Get("X-From-Cache"); got != "hit" {
+		t.Errorf("second response X-From-Cache = %q, want %q", got, "hit")
+	}
+
+	if requests != 1 {
+		t.Errorf("server saw %d requests, want 1 (second request should be served from cache)", requests)
+	}
+}
+
+// TestCachingRoundTripperRevalidatesStaleEntry verifies a cache entry past
+// its TTL is revalidated with If-None-Match, and a 304 response refreshes it
+// (tagged X-From-Cache: revalidated) without the caller ever re-fetching the
+// full body from scratch.
+func TestCachingRoundTripperRevalidatesStaleEntry(t *testing.T) {
+	withEnv(t, "RUNNER_TOOL_CACHE", t.TempDir())
+
+	var requests int
+	var sawIfNoneMatch string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if inm := r.Header.Get("If-None-Match"); inm != "" {
+			sawIfNoneMatch = inm
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte("hello"))
+	}))
+	t.Cleanup(srv.Close)
+
+	// ttl of 0 means every request is immediately stale, forcing
+	// revalidation instead of a plain cache hit.
+	rt := &cachingRoundTripper{next: http.DefaultTransport, cache: newHTTPCache(), ttl: 0}
+
+	req1, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	if err != nil {
+		t.Fatalf("http.NewRequest() error = %v", err)
+	}
+	resp1, err := rt.RoundTrip(req1)
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	resp1.Body.Close()
+
+	req2, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	if err != nil {
+		t.Fatalf("http.NewRequest() error = %v", err)
+	}
+	resp2, err := rt.RoundTrip(req2)
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	resp2.Body.Close()
+
+	if requests != 2 {
+		t.Errorf("server saw %d requests, want 2 (stale entry must be revalidated)", requests)
+	}
+	if sawIfNoneMatch != `"v1"` {
+		t.Errorf("revalidation request If-None-Match = %q, want %q", sawIfNoneMatch, `"v1"`)
+	}
+	if got := resp2.Header.Get("X-From-Cache"); got != "revalidated" {
+		t.Errorf("second response X-From-Cache = %q, want %q", got, "revalidated")
+	}
+}
@@ -0,0 +1,76 @@
+// Package forge abstracts the code-hosting API summary-issues talks to, so
+// the summary package never has to know whether it's rolling up issues from
+// GitHub, GitLab, Gitea, or Gerrit.
+package forge
+
+import (
+	"fmt"
+	"time"
+)
+
+// Issue is the forge-agnostic view of an issue returned by Forge.SearchIssues.
+type Issue struct {
+	ID        string
+	URL       string
+	Title     string
+	Body      string
+	Author    string
+	State     string
+	CreatedAt time.Time
+	Labels    []string
+	Comments  []Comment
+}
+
+// Comment is the forge-agnostic view of a single issue comment.
+type Comment struct {
+	Author    string
+	Body      string
+	UpdatedAt time.Time
+}
+
+// Forge is the subset of a code-hosting API summary-issues needs: searching
+// for issues and writing back a generated summary body. Every search method
+// takes plain (user, labelNames) arguments rather than a query string, so
+// each implementation can build a query in its own forge's syntax instead of
+// being handed GitHub search syntax to reinterpret. How several label names
+// combine follows each forge's own query syntax: GitHub's `label:"a","b"`
+// matches issues with any of them, while GitLab's `labels=a,b` and Gerrit's
+// `hashtag:a hashtag:b` match only issues with all of them.
+type Forge interface {
+	// SearchSummaryIssues returns the open issues owned by user that carry
+	// the "summary" label plus labelNames.
+	SearchSummaryIssues(user string, labelNames []string) ([]*Issue, error)
+	// SearchLabeledIssues returns all issues (regardless of state) owned by
+	// user that carry labelNames. If labelNames is empty, it returns
+	// no issues rather than everything owned by user.
+	SearchLabeledIssues(user string, labelNames []string) ([]*Issue, error)
+	// LabeledIssuesURL returns a human-browsable URL listing the issues
+	// SearchLabeledIssues(user, labelNames) would return, or "" if
+	// labelNames is empty.
+	LabeledIssuesURL(user string, labelNames []string) string
+	// UpdateIssueBody overwrites the body of the issue identified by id.
+	UpdateIssueBody(id, body string) error
+	// UpsertComment creates a comment on the issue identified by id, or, if a
+	// comment containing marker already exists, edits that comment in place
+	// instead of creating a duplicate.
+	UpsertComment(id, marker, body string) error
+	// AddComment always creates a new comment on the issue identified by id.
+	AddComment(id, body string) error
+}
+
+// New constructs the Forge selected by kind ("github", "gitlab", "gitea", or
+// "gerrit"); kind defaults to "github" when empty.
+func New(kind, user string) (Forge, error) {
+	switch kind {
+	case "", "github":
+		return &githubForge{user: user}, nil
+	case "gitlab":
+		return newGitLabForge(user)
+	case "gitea":
+		return newGiteaForge(user)
+	case "gerrit":
+		return newGerritForge(user)
+	default:
+		return nil, fmt.Errorf("unknown forge type %q", kind)
+	}
+}
@@ -0,0 +1,225 @@
+package forge
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// gerritForge talks to a Gerrit instance's REST API, whose JSON responses are
+// prefixed with a `)]}'` magic line to guard against XSSI and must be
+// stripped before unmarshalling.
+type gerritForge struct {
+	baseURL string
+	project string
+	cl      *http.Client
+}
+
+func newGerritForge(user string) (*gerritForge, error) {
+	baseURL := os.Getenv("GERRIT_API_URL")
+	if baseURL == "" {
+		return nil, fmt.Errorf("empty GERRIT_API_URL")
+	}
+	return &gerritForge{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		project: os.Getenv("GERRIT_PROJECT"),
+		cl:      newHTTPClient(),
+	}, nil
+}
+
+type gerritChange struct {
+	ID      string `json:"id"`
+	Number  int    `json:"_number"`
+	Subject string `json:"subject"`
+	Status  string `json:"status"`
+	Created string `json:"created"`
+	Owner   struct {
+		Username string `json:"username"`
+	} `json:"owner"`
+	Hashtags []string `json:"hashtags"`
+}
+
+// gerritTimeLayout is the timestamp format Gerrit's REST API uses, e.g.
+// "2024-01-15 10:04:05.000000000".
+const gerritTimeLayout = "2006-01-02 15:04:05.000000000"
+
+type gerritComment struct {
+	Author struct {
+		Username string `json:"username"`
+	} `json:"author"`
+	Message string `json:"message"`
+	Updated string `json:"updated"`
+}
+
+func (f *gerritForge) SearchSummaryIssues(user string, labelNames []string) ([]*Issue, error) {
+	labels := append([]string{"summary"}, labelNames...)
+	query := fmt.Sprintf("status:open owner:%s %s", user, gerritLabelQuery(labels))
+	return f.searchIssues(query)
+}
+
+func (f *gerritForge) SearchLabeledIssues(user string, labelNames []string) ([]*Issue, error) {
+	lq := gerritLabelQuery(labelNames)
+	if lq == "" {
+		return nil, nil
+	}
+	query := fmt.Sprintf("owner:%s %s", user, lq)
+	return f.searchIssues(query)
+}
+
+func (f *gerritForge) LabeledIssuesURL(user string, labelNames []string) string {
+	lq := gerritLabelQuery(labelNames)
+	if lq == "" {
+		return ""
+	}
+	query := fmt.Sprintf("owner:%s %s", user, lq)
+	return fmt.Sprintf("%s/q/%s", f.baseURL, url.QueryEscape(query))
+}
+
+func (f *gerritForge) searchIssues(query string) ([]*Issue, error) {
+	u := fmt.Sprintf("%s/changes/?q=%s", f.baseURL, url.QueryEscape(query))
+	var changes []gerritChange
+	if err := httpGetGerritJSON(f.cl, u, &changes); err != nil {
+		return nil, err
+	}
+
+	out := make([]*Issue, 0, len(changes))
+	for _, c := range changes {
+		commentsURL := fmt.Sprintf("%s/changes/%s/comments", f.baseURL, c.ID)
+		var commentsByFile map[string][]gerritComment
+		if err := httpGetGerritJSON(f.cl, commentsURL, &commentsByFile); err != nil {
+			return nil, err
+		}
+
+		createdAt, _ := time.Parse(gerritTimeLayout, c.Created)
+		fi := &Issue{
+			ID:        c.ID,
+			URL:       fmt.Sprintf("%s/c/%s/+/%d", f.baseURL, f.project, c.Number),
+			Title:     c.Subject,
+			Author:    c.Owner.Username,
+			State:     strings.ToLower(c.Status),
+			CreatedAt: createdAt,
+		}
+		fi.Labels = append(fi.Labels, c.Hashtags...)
+		for _, cs := range commentsByFile {
+			for _, cm := range cs {
+				updatedAt, _ := time.Parse(gerritTimeLayout, cm.Updated)
+				fi.Comments = append(fi.Comments, Comment{
+					Author:    cm.Author.Username,
+					Body:      cm.Message,
+					UpdatedAt: updatedAt,
+				})
+			}
+		}
+		sort.Slice(fi.Comments, func(i, j int) bool {
+			return fi.Comments[i].UpdatedAt.Before(fi.Comments[j].UpdatedAt)
+		})
+		out = append(out, fi)
+	}
+	return out, nil
+}
+
+func (f *gerritForge) UpdateIssueBody(id, body string) error {
+	u := fmt.Sprintf("%s/changes/%s/message", f.baseURL, id)
+	reqbody, err := json.Marshal(map[string]string{"message": body})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPut, u, bytes.NewReader(reqbody))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := f.cl.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		b, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("gerrit: non-200 response updating change %s: %s\n%s", id, resp.Status, b)
+	}
+	return nil
+}
+
+// gerritLabelQuery translates label names into Gerrit's hashtag search
+// operator, e.g. `hashtag:foo hashtag:bar`. Gerrit's own `label:` operator is
+// a code-review vote predicate (label:Code-Review=+2), not a tagging
+// mechanism, so the "labels" the rest of the tool rolls up by map onto
+// hashtags instead.
+func gerritLabelQuery(names []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+	parts := make([]string, 0, len(names))
+	for _, n := range names {
+		parts = append(parts, "hashtag:"+n)
+	}
+	return strings.Join(parts, " ")
+}
+
+// UpsertComment always posts a new review message: Gerrit's change messages
+// are an append-only log, so unlike the other forges there's no existing
+// comment to edit in place. marker is still included in the message body so
+// a human (or a future forge version with edit support) can still find it.
+func (f *gerritForge) UpsertComment(id, marker, body string) error {
+	return f.AddComment(id, marker+"\n"+body)
+}
+
+func (f *gerritForge) AddComment(id, body string) error {
+	u := fmt.Sprintf("%s/changes/%s/revisions/current/review", f.baseURL, id)
+	reqbody, err := json.Marshal(map[string]string{"message": body})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, u, bytes.NewReader(reqbody))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := f.cl.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		b, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("gerrit: non-200 response posting review on change %s: %s\n%s", id, resp.Status, b)
+	}
+	return nil
+}
+
+// gerritJSONPrefix is prepended by Gerrit to every REST JSON response to
+// prevent cross-site script inclusion attacks; it must be stripped before
+// the body can be unmarshalled.
+const gerritJSONPrefix = ")]}'"
+
+// httpGetGerritJSON GETs u and decodes the response into v, stripping
+// Gerrit's `)]}'` XSSI-prevention prefix first.
+func httpGetGerritJSON(cl *http.Client, u string, v interface{}) error {
+	resp, err := cl.Get(u)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("gerrit: error reading response from %s: %w", u, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("gerrit: non-200 response from %s: %s\n%s", u, resp.Status, body)
+	}
+
+	body = bytes.TrimPrefix(body, []byte(gerritJSONPrefix))
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("gerrit: error decoding json response from %s: %w", u, err)
+	}
+	return nil
+}
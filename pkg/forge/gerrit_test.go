@@ -0,0 +1,143 @@
+package forge
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// fakeGerritServer stands in for a Gerrit instance: every JSON response is
+// prefixed with the `)]}'` XSSI guard, mirroring real Gerrit behavior.
+// commentsByFile is returned for every /comments request, regardless of
+// change ID.
+func fakeGerritServer(t *testing.T, changes []gerritChange, commentsByFile map[string][]gerritComment) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(gerritJSONPrefix))
+		switch {
+		case strings.Contains(r.URL.Path, "/comments"):
+			json.NewEncoder(w).Encode(commentsByFile)
+		case strings.HasPrefix(r.URL.Path, "/changes/"):
+			json.NewEncoder(w).Encode(changes)
+		default:
+			t.Fatalf("fakeGerritServer: unexpected request %s", r.URL.Path)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestGerritForgeSearchSummaryIssues(t *testing.T) {
+	srv := fakeGerritServer(t, []gerritChange{{
+		ID:       "acme~widgets~I1",
+		Number:   1,
+		Subject:  "Widgets are broken",
+		Status:   "NEW",
+		Created:  "2024-01-15 00:00:00.000000000",
+		Hashtags: []string{"team-a"},
+	}}, map[string][]gerritComment{})
+
+	f := &gerritForge{baseURL: srv.URL, project: "acme/widgets", cl: srv.Client()}
+	issues, err := f.SearchSummaryIssues("alice", []string{"team-a"})
+	if err != nil {
+		t.Fatalf("SearchSummaryIssues() error = %v", err)
+	}
+	if len(issues) != 1 || issues[0].Title != "Widgets are broken" {
+		t.Errorf("SearchSummaryIssues() = %+v", issues)
+	}
+	if len(issues[0].Labels) != 1 || issues[0].Labels[0] != "team-a" {
+		t.Errorf("SearchSummaryIssues() labels = %v, want [team-a]", issues[0].Labels)
+	}
+}
+
+// TestGerritForgeSearchParsesAndOrdersComments guards against two bugs: a
+// comment's UpdatedAt silently staying zero-valued, and the returned order
+// depending on Go's randomized map iteration over the per-file comments
+// response instead of the comments' own timestamps.
+func TestGerritForgeSearchParsesAndOrdersComments(t *testing.T) {
+	srv := fakeGerritServer(t, []gerritChange{{
+		ID:      "acme~widgets~I1",
+		Number:  1,
+		Subject: "Widgets are broken",
+		Status:  "NEW",
+		Created: "2024-01-15 00:00:00.000000000",
+	}}, map[string][]gerritComment{
+		"a.go": {{Message: "second", Updated: "2024-01-16 00:00:00.000000000"}},
+		"b.go": {{Message: "first", Updated: "2024-01-15 12:00:00.000000000"}},
+	})
+
+	f := &gerritForge{baseURL: srv.URL, project: "acme/widgets", cl: srv.Client()}
+	issues, err := f.SearchSummaryIssues("alice", nil)
+	if err != nil {
+		t.Fatalf("SearchSummaryIssues() error = %v", err)
+	}
+	if len(issues) != 1 || len(issues[0].Comments) != 2 {
+		t.Fatalf("SearchSummaryIssues() = %+v", issues)
+	}
+
+	comments := issues[0].Comments
+	if comments[0].Body != "first" || comments[1].Body != "second" {
+		t.Errorf("comments not sorted by UpdatedAt: %+v", comments)
+	}
+	if comments[0].UpdatedAt.IsZero() || comments[1].UpdatedAt.IsZero() {
+		t.Errorf("comments have zero-valued UpdatedAt: %+v", comments)
+	}
+}
+
+func TestGerritForgeSearchLabeledIssuesNoLabels(t *testing.T) {
+	f := &gerritForge{baseURL: "http://unused.invalid", project: "acme/widgets"}
+	issues, err := f.SearchLabeledIssues("alice", nil)
+	if err != nil {
+		t.Fatalf("SearchLabeledIssues() error = %v", err)
+	}
+	if issues != nil {
+		t.Errorf("SearchLabeledIssues(nil labels) = %v, want nil", issues)
+	}
+}
+
+func TestGerritForgeLabeledIssuesURL(t *testing.T) {
+	f := &gerritForge{baseURL: "https://gerrit.example"}
+	if got := f.LabeledIssuesURL("alice", nil); got != "" {
+		t.Errorf("LabeledIssuesURL(nil labels) = %q, want empty", got)
+	}
+	got := f.LabeledIssuesURL("alice", []string{"bug"})
+	want := "https://gerrit.example/q/owner%3Aalice+hashtag%3Abug"
+	if got != want {
+		t.Errorf("LabeledIssuesURL() = %q, want %q", got, want)
+	}
+}
+
+func TestGerritLabelQuery(t *testing.T) {
+	if got, want := gerritLabelQuery(nil), ""; got != want {
+		t.Errorf("gerritLabelQuery(nil) = %q, want %q", got, want)
+	}
+	if got, want := gerritLabelQuery([]string{"bug", "p1"}), "hashtag:bug hashtag:p1"; got != want {
+		t.Errorf("gerritLabelQuery() = %q, want %q", got, want)
+	}
+}
+
+// TestGerritForgeUpsertCommentAlwaysPosts documents Gerrit's append-only
+// change-message log: UpsertComment can't edit a prior message in place, so
+// every call must hit the review endpoint.
+func TestGerritForgeUpsertCommentAlwaysPosts(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(srv.Close)
+
+	f := &gerritForge{baseURL: srv.URL, cl: srv.Client()}
+	if err := f.UpsertComment("acme~widgets~I1", "<!-- marker -->", "hello"); err != nil {
+		t.Fatalf("UpsertComment() error = %v", err)
+	}
+	if err := f.UpsertComment("acme~widgets~I1", "<!-- marker -->", "hello again"); err != nil {
+		t.Fatalf("UpsertComment() error = %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("server saw %d requests, want 2", requests)
+	}
+}
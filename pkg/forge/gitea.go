@@ -0,0 +1,147 @@
+package forge
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"code.gitea.io/sdk/gitea"
+)
+
+// giteaForge talks to a Gitea instance via its generated swagger SDK.
+type giteaForge struct {
+	client *gitea.Client
+	owner  string
+	repo   string
+}
+
+func newGiteaForge(user string) (*giteaForge, error) {
+	baseURL := os.Getenv("GITEA_API_URL")
+	if baseURL == "" {
+		return nil, fmt.Errorf("empty GITEA_API_URL")
+	}
+	token := os.Getenv("GITEA_TOKEN")
+	if token == "" {
+		return nil, fmt.Errorf("empty GITEA_TOKEN")
+	}
+	client, err := gitea.NewClient(baseURL, gitea.SetToken(token), gitea.SetHTTPClient(newHTTPClient()))
+	if err != nil {
+		return nil, fmt.Errorf("gitea: unable to create client: %w", err)
+	}
+	return &giteaForge{
+		client: client,
+		owner:  user,
+		repo:   os.Getenv("GITEA_REPO"),
+	}, nil
+}
+
+func (f *giteaForge) SearchSummaryIssues(user string, labelNames []string) ([]*Issue, error) {
+	return f.searchIssues(gitea.StateOpen, append([]string{"summary"}, labelNames...))
+}
+
+func (f *giteaForge) SearchLabeledIssues(user string, labelNames []string) ([]*Issue, error) {
+	if len(labelNames) == 0 {
+		return nil, nil
+	}
+	return f.searchIssues(gitea.StateAll, labelNames)
+}
+
+func (f *giteaForge) LabeledIssuesURL(user string, labelNames []string) string {
+	if len(labelNames) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s/%s/%s/issues?labels=%s", os.Getenv("GITEA_API_URL"), f.owner, f.repo, url.QueryEscape(giteaLabelQuery(labelNames)))
+}
+
+func (f *giteaForge) searchIssues(state gitea.StateType, labelNames []string) ([]*Issue, error) {
+	opt := gitea.ListIssueOption{State: state, Labels: labelNames}
+	issues, _, err := f.client.ListRepoIssues(f.owner, f.repo, opt)
+	if err != nil {
+		return nil, fmt.Errorf("gitea: unable to search issues: %w", err)
+	}
+
+	out := make([]*Issue, 0, len(issues))
+	for _, i := range issues {
+		comments, _, err := f.client.ListIssueComments(f.owner, f.repo, i.Index, gitea.ListIssueCommentOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("gitea: unable to list comments for issue #%d: %w", i.Index, err)
+		}
+		fi := &Issue{
+			ID:        fmt.Sprintf("%d", i.Index),
+			URL:       i.HTMLURL,
+			Title:     i.Title,
+			Body:      i.Body,
+			Author:    i.Poster.UserName,
+			State:     string(i.State),
+			CreatedAt: i.Created,
+		}
+		for _, l := range i.Labels {
+			fi.Labels = append(fi.Labels, l.Name)
+		}
+		for _, c := range comments {
+			fi.Comments = append(fi.Comments, Comment{
+				Author:    c.Poster.UserName,
+				Body:      c.Body,
+				UpdatedAt: c.Updated,
+			})
+		}
+		out = append(out, fi)
+	}
+	return out, nil
+}
+
+func (f *giteaForge) UpdateIssueBody(id, body string) error {
+	index, err := giteaIssueIndex(id)
+	if err != nil {
+		return err
+	}
+	_, _, err = f.client.EditIssue(f.owner, f.repo, index, gitea.EditIssueOption{
+		Body: &body,
+	})
+	return err
+}
+
+// giteaLabelQuery translates label names into Gitea's comma-joined label
+// query parameter.
+func giteaLabelQuery(names []string) string {
+	return strings.Join(names, ",")
+}
+
+func (f *giteaForge) UpsertComment(id, marker, body string) error {
+	index, err := giteaIssueIndex(id)
+	if err != nil {
+		return err
+	}
+
+	comments, _, err := f.client.ListIssueComments(f.owner, f.repo, index, gitea.ListIssueCommentOptions{})
+	if err != nil {
+		return fmt.Errorf("gitea: unable to list comments for issue #%d: %w", index, err)
+	}
+	for _, c := range comments {
+		if strings.Contains(c.Body, marker) {
+			_, _, err := f.client.EditIssueComment(f.owner, f.repo, c.ID, gitea.EditIssueCommentOption{Body: body})
+			return err
+		}
+	}
+
+	_, _, err = f.client.CreateIssueComment(f.owner, f.repo, index, gitea.CreateIssueCommentOption{Body: body})
+	return err
+}
+
+func (f *giteaForge) AddComment(id, body string) error {
+	index, err := giteaIssueIndex(id)
+	if err != nil {
+		return err
+	}
+	_, _, err = f.client.CreateIssueComment(f.owner, f.repo, index, gitea.CreateIssueCommentOption{Body: body})
+	return err
+}
+
+func giteaIssueIndex(id string) (int64, error) {
+	var index int64
+	if _, err := fmt.Sscanf(id, "%d", &index); err != nil {
+		return 0, fmt.Errorf("gitea: invalid issue id %q: %w", id, err)
+	}
+	return index, nil
+}
@@ -0,0 +1,92 @@
+package forge
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"code.gitea.io/sdk/gitea"
+)
+
+// fakeGiteaServer stands in for a Gitea instance: it answers the /version
+// probe the SDK issues on client creation, one repo issues listing (always
+// returning issues), and an empty comments listing for any issue.
+func fakeGiteaServer(t *testing.T, issues []gitea.Issue) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/version"):
+			json.NewEncoder(w).Encode(map[string]string{"version": "1.20.0"})
+		case strings.Contains(r.URL.Path, "/comments"):
+			json.NewEncoder(w).Encode([]gitea.Comment{})
+		case strings.HasSuffix(r.URL.Path, "/issues"):
+			json.NewEncoder(w).Encode(issues)
+		default:
+			t.Fatalf("fakeGiteaServer: unexpected request %s", r.URL.Path)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newTestGiteaForge(t *testing.T, srv *httptest.Server) *giteaForge {
+	t.Helper()
+	client, err := gitea.NewClient(srv.URL, gitea.SetHTTPClient(srv.Client()))
+	if err != nil {
+		t.Fatalf("gitea.NewClient() error = %v", err)
+	}
+	return &giteaForge{client: client, owner: "acme", repo: "widgets"}
+}
+
+func TestGiteaForgeSearchSummaryIssues(t *testing.T) {
+	srv := fakeGiteaServer(t, []gitea.Issue{{
+		Index:   1,
+		HTMLURL: "https://gitea.example/acme/widgets/issues/1",
+		Title:   "Widgets are broken",
+		Poster:  &gitea.User{UserName: "alice"},
+		State:   gitea.StateOpen,
+		Labels:  []*gitea.Label{{Name: "summary"}, {Name: "team-a"}},
+	}})
+	f := newTestGiteaForge(t, srv)
+
+	issues, err := f.SearchSummaryIssues("acme", []string{"team-a"})
+	if err != nil {
+		t.Fatalf("SearchSummaryIssues() error = %v", err)
+	}
+	if len(issues) != 1 || issues[0].Title != "Widgets are broken" {
+		t.Errorf("SearchSummaryIssues() = %+v", issues)
+	}
+	if len(issues[0].Labels) != 2 {
+		t.Errorf("SearchSummaryIssues() labels = %v, want 2", issues[0].Labels)
+	}
+}
+
+func TestGiteaForgeSearchLabeledIssuesNoLabels(t *testing.T) {
+	f := &giteaForge{owner: "acme", repo: "widgets"}
+	issues, err := f.SearchLabeledIssues("acme", nil)
+	if err != nil {
+		t.Fatalf("SearchLabeledIssues() error = %v", err)
+	}
+	if issues != nil {
+		t.Errorf("SearchLabeledIssues(nil labels) = %v, want nil", issues)
+	}
+}
+
+func TestGiteaLabelQuery(t *testing.T) {
+	if got, want := giteaLabelQuery([]string{"bug", "p1"}), "bug,p1"; got != want {
+		t.Errorf("giteaLabelQuery() = %q, want %q", got, want)
+	}
+}
+
+func TestGiteaIssueIndex(t *testing.T) {
+	idx, err := giteaIssueIndex("42")
+	if err != nil || idx != 42 {
+		t.Errorf("giteaIssueIndex(%q) = (%d, %v), want (42, nil)", "42", idx, err)
+	}
+	if _, err := giteaIssueIndex("not-a-number"); err == nil {
+		t.Error("giteaIssueIndex(not-a-number) error = nil, want non-nil")
+	}
+}
@@ -0,0 +1,325 @@
+package forge
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// githubForge is the default Forge, backed by GitHub's GraphQL API.
+type githubForge struct {
+	user string
+}
+
+func (f *githubForge) SearchSummaryIssues(user string, labelNames []string) ([]*Issue, error) {
+	query := fmt.Sprintf("is:open user:%s label:summary %s", user, githubLabelQuery(labelNames))
+	return searchForgeIssues(query)
+}
+
+func (f *githubForge) SearchLabeledIssues(user string, labelNames []string) ([]*Issue, error) {
+	lq := githubLabelQuery(labelNames)
+	if lq == "" {
+		return nil, nil
+	}
+	query := fmt.Sprintf("user:%s %s", user, lq)
+	return searchForgeIssues(query)
+}
+
+func (f *githubForge) LabeledIssuesURL(user string, labelNames []string) string {
+	lq := githubLabelQuery(labelNames)
+	if lq == "" {
+		return ""
+	}
+	query := fmt.Sprintf("type:issue user:%s %s", user, lq)
+	return searchURL(query)
+}
+
+func searchForgeIssues(query string) ([]*Issue, error) {
+	issues, err := searchIssues(query)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*Issue, 0, len(issues))
+	for _, i := range issues {
+		out = append(out, graphqlIssueToForgeIssue(i))
+	}
+	return out, nil
+}
+
+func (f *githubForge) UpdateIssueBody(id, body string) error {
+	return graphql(`
+		mutation UpdateIssue ($id: String!, $body: String!) {
+			updateIssue(input: {
+				id: $id,
+				body: $body
+			}) {
+				clientMutationId
+			}
+		}
+	`, map[string]interface{}{
+		"id":   id,
+		"body": body,
+	}, nil)
+}
+
+// githubLabelQuery translates label names into GitHub search syntax, e.g.
+// `label:"foo","bar"`.
+func githubLabelQuery(names []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+	qs := make([]string, len(names))
+	for i, n := range names {
+		qs[i] = fmt.Sprintf("%q", n)
+	}
+	return "label:" + strings.Join(qs, ",")
+}
+
+func (f *githubForge) UpsertComment(id, marker, body string) error {
+	data := struct {
+		Node struct {
+			Comments struct {
+				Nodes []struct {
+					ID   string `json:"id"`
+					Body string `json:"body"`
+				} `json:"nodes"`
+			} `json:"comments"`
+		} `json:"node"`
+	}{}
+	err := graphql(`
+		query IssueComments ($id: ID!) {
+			node(id: $id) {
+				... on Issue {
+					comments(last: 100) {
+						nodes {
+							id
+							body
+						}
+					}
+				}
+			}
+		}
+	`, map[string]interface{}{
+		"id": id,
+	}, &data)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range data.Node.Comments.Nodes {
+		if strings.Contains(c.Body, marker) {
+			return graphql(`
+				mutation UpdateIssueComment ($id: String!, $body: String!) {
+					updateIssueComment(input: {
+						id: $id,
+						body: $body
+					}) {
+						clientMutationId
+					}
+				}
+			`, map[string]interface{}{
+				"id":   c.ID,
+				"body": body,
+			}, nil)
+		}
+	}
+
+	return f.AddComment(id, body)
+}
+
+func (f *githubForge) AddComment(id, body string) error {
+	return graphql(`
+		mutation AddComment ($id: String!, $body: String!) {
+			addComment(input: {
+				subjectId: $id,
+				body: $body
+			}) {
+				clientMutationId
+			}
+		}
+	`, map[string]interface{}{
+		"id":   id,
+		"body": body,
+	}, nil)
+}
+
+func graphqlIssueToForgeIssue(i *graphqlIssue) *Issue {
+	fi := &Issue{
+		ID:        i.ID,
+		URL:       i.URL,
+		Title:     i.Title,
+		Body:      i.Body,
+		Author:    i.Author.Login,
+		State:     i.State,
+		CreatedAt: i.CreatedAt,
+	}
+	for _, l := range i.Labels.Nodes {
+		fi.Labels = append(fi.Labels, l.Name)
+	}
+	for _, c := range i.Comments.Nodes {
+		fi.Comments = append(fi.Comments, Comment{
+			Author:    c.Author.Login,
+			Body:      c.Body,
+			UpdatedAt: c.UpdatedAt,
+		})
+	}
+	return fi
+}
+
+func searchIssues(query string) ([]*graphqlIssue, error) {
+	fmt.Printf("searching issues %s\n", query)
+
+	data := struct {
+		Search struct {
+			Nodes []*graphqlIssue `json:"nodes"`
+		} `json:"search"`
+	}{}
+	err := graphql(`
+		query SearchIssues ($query: String!) {
+			search(type: ISSUE, first: 100, query: $query) {
+				nodes {
+					... on Issue {
+						id
+						url
+						title
+						body
+						state
+						createdAt
+						author {
+							login
+						}
+						labels(first: 100) {
+							nodes {
+								name
+							}
+						}
+						comments(last: 100) {
+							nodes {
+								author {
+									login
+								}
+								body
+								updatedAt
+							}
+						}
+					}
+				}
+			}
+		}
+	`, map[string]interface{}{
+		"query": query,
+	}, &data)
+	if err != nil {
+		return nil, err
+	}
+	return data.Search.Nodes, nil
+}
+
+func graphql(query string, variables map[string]interface{}, responseData interface{}) error {
+	reqbody, err := json.Marshal(map[string]interface{}{
+		"query":     query,
+		"variables": variables,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal query %s and variables %s: %w", query, variables, err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, os.Getenv("GITHUB_GRAPHQL_URL"), bytes.NewBuffer(reqbody))
+	if err != nil {
+		return err
+	}
+
+	token := os.Getenv("GITHUB_TOKEN")
+	if token == "" {
+		return fmt.Errorf("empty GITHUB_TOKEN")
+	}
+	req.Header.Set("Authorization", "bearer "+token)
+
+	reqdump, err := httputil.DumpRequestOut(req, true)
+	if err != nil {
+		return fmt.Errorf("error dumping request: %w", err)
+	}
+
+	cl := newHTTPClient()
+	resp, err := cl.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if fromCache := resp.Header.Get("X-From-Cache"); fromCache != "" {
+		fmt.Printf("graphql response served from cache (%s)\n", fromCache)
+	}
+
+	respdump, err := httputil.DumpResponse(resp, true)
+	if err != nil {
+		return fmt.Errorf("error dumping response: %w", err)
+	}
+
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("non-200 response:\n%s\n\nrequest:\n%s", string(respdump), string(reqdump))
+	}
+
+	response := struct {
+		Data   interface{}
+		Errors []struct {
+			Type    string   `json:"type"`
+			Path    []string `json:"path"`
+			Message string   `json:"message"`
+		} `json:"errors"`
+	}{
+		Data: responseData,
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return fmt.Errorf("error decoding json response:\n%s\n%w", respdump, err)
+	}
+
+	if len(response.Errors) > 0 {
+		return fmt.Errorf("graphql error: %s\nrequest:\n%s", response.Errors[0].Message, reqdump)
+	}
+
+	return nil
+}
+
+type graphqlIssue struct {
+	ID        string    `json:"id"`
+	URL       string    `json:"url"`
+	Title     string    `json:"title"`
+	Body      string    `json:"body"`
+	State     string    `json:"state"`
+	CreatedAt time.Time `json:"createdAt"`
+	Author    actor     `json:"author"`
+	Labels    struct {
+		Nodes []label `json:"nodes"`
+	} `json:"labels"`
+	Comments struct {
+		Nodes []graphqlComment `json:"nodes"`
+	} `json:"comments"`
+}
+
+type label struct {
+	Name string `json:"name"`
+}
+
+type actor struct {
+	Login string `json:"login"`
+}
+
+type graphqlComment struct {
+	Author    actor     `json:"author"`
+	Body      string    `json:"body"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+func searchURL(query string) string {
+	q := url.Values{}
+	q.Set("q", query)
+	return os.Getenv("GITHUB_SERVER_URL") + "/search?" + q.Encode()
+}
@@ -0,0 +1,174 @@
+package forge
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+// fakeGraphQLServer stands in for the GitHub GraphQL endpoint, returning
+// resp (marshalled as JSON) for every request it receives.
+func fakeGraphQLServer(t *testing.T, resp interface{}) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			t.Fatalf("fakeGraphQLServer: error encoding response: %v", err)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func withEnv(t *testing.T, key, value string) {
+	t.Helper()
+	old, had := os.LookupEnv(key)
+	if err := os.Setenv(key, value); err != nil {
+		t.Fatalf("os.Setenv(%q, %q): %v", key, value, err)
+	}
+	t.Cleanup(func() {
+		if had {
+			os.Setenv(key, old)
+		} else {
+			os.Unsetenv(key)
+		}
+	})
+}
+
+func TestGithubForgeSearchIssues(t *testing.T) {
+	srv := fakeGraphQLServer(t, map[string]interface{}{
+		"data": map[string]interface{}{
+			"search": map[string]interface{}{
+				"nodes": []map[string]interface{}{
+					{
+						"id":        "issue-1",
+						"url":       "https://github.com/acme/widgets/issues/1",
+						"title":     "Widgets are broken",
+						"body":      "details",
+						"state":     "OPEN",
+						"createdAt": "2024-01-15T00:00:00Z",
+						"author":    map[string]interface{}{"login": "alice"},
+						"labels": map[string]interface{}{
+							"nodes": []map[string]interface{}{{"name": "bug"}},
+						},
+						"comments": map[string]interface{}{
+							"nodes": []map[string]interface{}{
+								{
+									"author":    map[string]interface{}{"login": "bob"},
+									"body":      "looking into it",
+									"updatedAt": "2024-01-16T00:00:00Z",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+
+	withEnv(t, "GITHUB_GRAPHQL_URL", srv.URL)
+	withEnv(t, "GITHUB_TOKEN", "fake-token")
+	withEnv(t, "RUNNER_TOOL_CACHE", t.TempDir())
+
+	f := &githubForge{user: "acme"}
+	issues, err := f.SearchLabeledIssues("acme", []string{"bug"})
+	if err != nil {
+		t.Fatalf("SearchLabeledIssues() error = %v", err)
+	}
+	if len(issues) != 1 {
+		t.Fatalf("SearchLabeledIssues() returned %d issues, want 1", len(issues))
+	}
+
+	got := issues[0]
+	if got.ID != "issue-1" || got.Title != "Widgets are broken" || got.Author != "alice" {
+		t.Errorf("SearchLabeledIssues() issue = %+v", got)
+	}
+	if len(got.Labels) != 1 || got.Labels[0] != "bug" {
+		t.Errorf("SearchLabeledIssues() labels = %v, want [bug]", got.Labels)
+	}
+	if len(got.Comments) != 1 || got.Comments[0].Body != "looking into it" {
+		t.Errorf("SearchLabeledIssues() comments = %+v", got.Comments)
+	}
+}
+
+func TestGithubForgeSearchLabeledIssuesNoLabels(t *testing.T) {
+	f := &githubForge{user: "acme"}
+	issues, err := f.SearchLabeledIssues("acme", nil)
+	if err != nil {
+		t.Fatalf("SearchLabeledIssues() error = %v", err)
+	}
+	if issues != nil {
+		t.Errorf("SearchLabeledIssues(nil labels) = %v, want nil", issues)
+	}
+}
+
+func TestGithubForgeGraphQLError(t *testing.T) {
+	srv := fakeGraphQLServer(t, map[string]interface{}{
+		"errors": []map[string]interface{}{
+			{"message": "something went wrong"},
+		},
+	})
+
+	withEnv(t, "GITHUB_GRAPHQL_URL", srv.URL)
+	withEnv(t, "GITHUB_TOKEN", "fake-token")
+	withEnv(t, "RUNNER_TOOL_CACHE", t.TempDir())
+
+	f := &githubForge{user: "acme"}
+	if _, err := f.SearchSummaryIssues("acme", nil); err == nil {
+		t.Fatal("SearchSummaryIssues() error = nil, want non-nil")
+	}
+}
+
+func TestGithubLabelQuery(t *testing.T) {
+	if got, want := githubLabelQuery(nil), ""; got != want {
+		t.Errorf("githubLabelQuery(nil) = %q, want %q", got, want)
+	}
+	if got, want := githubLabelQuery([]string{"bug", "p1"}), `label:"bug","p1"`; got != want {
+		t.Errorf("githubLabelQuery() = %q, want %q", got, want)
+	}
+}
+
+// TestGithubForgeAddCommentNotCached guards against regressing into caching
+// mutations: two identical AddComment calls within the cache TTL must both
+// reach the server, not have the second one silently served a cached
+// "success" from the first without ever posting.
+func TestGithubForgeAddCommentNotCached(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{}})
+	}))
+	t.Cleanup(srv.Close)
+
+	withEnv(t, "GITHUB_GRAPHQL_URL", srv.URL)
+	withEnv(t, "GITHUB_TOKEN", "fake-token")
+	withEnv(t, "RUNNER_TOOL_CACHE", t.TempDir())
+
+	f := &githubForge{user: "acme"}
+	if err := f.AddComment("issue-1", "hello"); err != nil {
+		t.Fatalf("AddComment() error = %v", err)
+	}
+	if err := f.AddComment("issue-1", "hello"); err != nil {
+		t.Fatalf("AddComment() error = %v", err)
+	}
+
+	if requests != 2 {
+		t.Errorf("server saw %d requests, want 2 (second AddComment must not be served from cache)", requests)
+	}
+}
+
+func TestGithubForgeLabeledIssuesURL(t *testing.T) {
+	withEnv(t, "GITHUB_SERVER_URL", "https://github.com")
+	f := &githubForge{user: "acme"}
+	if got := f.LabeledIssuesURL("acme", nil); got != "" {
+		t.Errorf("LabeledIssuesURL(nil labels) = %q, want empty", got)
+	}
+	got := f.LabeledIssuesURL("acme", []string{"bug"})
+	want := "https://github.com/search?q=type%3Aissue+user%3Aacme+label%3A%22bug%22"
+	if got != want {
+		t.Errorf("LabeledIssuesURL() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,200 @@
+package forge
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// gitlabForge talks to the GitLab REST v4 API.
+type gitlabForge struct {
+	baseURL string
+	project string
+	token   string
+	cl      *http.Client
+}
+
+func newGitLabForge(user string) (*gitlabForge, error) {
+	baseURL := os.Getenv("GITLAB_API_URL")
+	if baseURL == "" {
+		baseURL = "https://gitlab.com/api/v4"
+	}
+	token := os.Getenv("GITLAB_TOKEN")
+	if token == "" {
+		return nil, fmt.Errorf("empty GITLAB_TOKEN")
+	}
+	return &gitlabForge{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		project: os.Getenv("GITLAB_PROJECT"),
+		token:   token,
+		cl:      newHTTPClient(),
+	}, nil
+}
+
+type gitlabIssue struct {
+	IID         int       `json:"iid"`
+	WebURL      string    `json:"web_url"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	State       string    `json:"state"`
+	CreatedAt   time.Time `json:"created_at"`
+	Author      struct {
+		Username string `json:"username"`
+	} `json:"author"`
+	Labels []string `json:"labels"`
+}
+
+type gitlabNote struct {
+	Author struct {
+		Username string `json:"username"`
+	} `json:"author"`
+	Body      string    `json:"body"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (f *gitlabForge) SearchSummaryIssues(user string, labelNames []string) ([]*Issue, error) {
+	labels := append([]string{"summary"}, labelNames...)
+	return f.searchIssues("state=opened&" + gitlabLabelQuery(labels))
+}
+
+func (f *gitlabForge) SearchLabeledIssues(user string, labelNames []string) ([]*Issue, error) {
+	lq := gitlabLabelQuery(labelNames)
+	if lq == "" {
+		return nil, nil
+	}
+	return f.searchIssues(lq)
+}
+
+func (f *gitlabForge) LabeledIssuesURL(user string, labelNames []string) string {
+	if len(labelNames) == 0 {
+		return ""
+	}
+	q := url.Values{}
+	for _, n := range labelNames {
+		q.Add("label_name[]", n)
+	}
+	webURL := strings.TrimSuffix(f.baseURL, "/api/v4")
+	return fmt.Sprintf("%s/%s/-/issues?%s", webURL, f.project, q.Encode())
+}
+
+func (f *gitlabForge) searchIssues(query string) ([]*Issue, error) {
+	u := fmt.Sprintf("%s/projects/%s/issues?%s", f.baseURL, url.PathEscape(f.project), query)
+	var issues []gitlabIssue
+	if err := f.getJSON(u, &issues); err != nil {
+		return nil, err
+	}
+
+	out := make([]*Issue, 0, len(issues))
+	for _, i := range issues {
+		notesURL := fmt.Sprintf("%s/projects/%s/issues/%d/notes?sort=asc", f.baseURL, url.PathEscape(f.project), i.IID)
+		var notes []gitlabNote
+		if err := f.getJSON(notesURL, &notes); err != nil {
+			return nil, err
+		}
+		fi := &Issue{
+			ID:        fmt.Sprintf("%d", i.IID),
+			URL:       i.WebURL,
+			Title:     i.Title,
+			Body:      i.Description,
+			Author:    i.Author.Username,
+			State:     i.State,
+			CreatedAt: i.CreatedAt,
+			Labels:    i.Labels,
+		}
+		for _, n := range notes {
+			fi.Comments = append(fi.Comments, Comment{
+				Author:    n.Author.Username,
+				Body:      n.Body,
+				UpdatedAt: n.UpdatedAt,
+			})
+		}
+		out = append(out, fi)
+	}
+	return out, nil
+}
+
+func (f *gitlabForge) UpdateIssueBody(id, body string) error {
+	u := fmt.Sprintf("%s/projects/%s/issues/%s", f.baseURL, url.PathEscape(f.project), id)
+	return f.sendJSON(http.MethodPut, u, map[string]string{"description": body})
+}
+
+// gitlabLabelQuery translates label names into a GitLab issues-list query
+// fragment, e.g. `labels=foo,bar`.
+func gitlabLabelQuery(names []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+	return "labels=" + strings.Join(names, ",")
+}
+
+func (f *gitlabForge) UpsertComment(id, marker, body string) error {
+	notesURL := fmt.Sprintf("%s/projects/%s/issues/%s/notes", f.baseURL, url.PathEscape(f.project), id)
+	var notes []struct {
+		ID   int    `json:"id"`
+		Body string `json:"body"`
+	}
+	if err := f.getJSON(notesURL, &notes); err != nil {
+		return err
+	}
+
+	for _, n := range notes {
+		if strings.Contains(n.Body, marker) {
+			u := fmt.Sprintf("%s/%d", notesURL, n.ID)
+			return f.sendJSON(http.MethodPut, u, map[string]string{"body": body})
+		}
+	}
+
+	return f.sendJSON(http.MethodPost, notesURL, map[string]string{"body": body})
+}
+
+func (f *gitlabForge) AddComment(id, body string) error {
+	notesURL := fmt.Sprintf("%s/projects/%s/issues/%s/notes", f.baseURL, url.PathEscape(f.project), id)
+	return f.sendJSON(http.MethodPost, notesURL, map[string]string{"body": body})
+}
+
+func (f *gitlabForge) sendJSON(method, u string, payload interface{}) error {
+	reqbody, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(method, u, bytes.NewReader(reqbody))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("PRIVATE-TOKEN", f.token)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := f.cl.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		b, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("gitlab: non-200 response from %s %s: %s\n%s", method, u, resp.Status, b)
+	}
+	return nil
+}
+
+func (f *gitlabForge) getJSON(u string, v interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("PRIVATE-TOKEN", f.token)
+	resp, err := f.cl.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		b, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("gitlab: non-200 response from %s: %s\n%s", u, resp.Status, b)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
@@ -0,0 +1,74 @@
+package forge
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func mustParseTime(t *testing.T, s string) time.Time {
+	t.Helper()
+	tm, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		t.Fatalf("time.Parse(%q): %v", s, err)
+	}
+	return tm
+}
+
+func fakeGitLabServer(t *testing.T, issues []gitlabIssue) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/notes"):
+			json.NewEncoder(w).Encode([]gitlabNote{})
+		default:
+			json.NewEncoder(w).Encode(issues)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestGitLabForgeSearchSummaryIssues(t *testing.T) {
+	srv := fakeGitLabServer(t, []gitlabIssue{{
+		IID:       1,
+		WebURL:    "https://gitlab.com/acme/widgets/-/issues/1",
+		Title:     "Widgets are broken",
+		State:     "opened",
+		CreatedAt: mustParseTime(t, "2024-01-15T00:00:00Z"),
+		Labels:    []string{"summary", "team-a"},
+	}})
+
+	f := &gitlabForge{baseURL: srv.URL, project: "acme/widgets", token: "fake-token", cl: http.DefaultClient}
+	issues, err := f.SearchSummaryIssues("acme", []string{"team-a"})
+	if err != nil {
+		t.Fatalf("SearchSummaryIssues() error = %v", err)
+	}
+	if len(issues) != 1 || issues[0].Title != "Widgets are broken" {
+		t.Errorf("SearchSummaryIssues() = %+v", issues)
+	}
+}
+
+func TestGitLabForgeSearchLabeledIssuesNoLabels(t *testing.T) {
+	f := &gitlabForge{baseURL: "http://unused.invalid", project: "acme/widgets", token: "fake-token", cl: http.DefaultClient}
+	issues, err := f.SearchLabeledIssues("acme", nil)
+	if err != nil {
+		t.Fatalf("SearchLabeledIssues() error = %v", err)
+	}
+	if issues != nil {
+		t.Errorf("SearchLabeledIssues(nil labels) = %v, want nil", issues)
+	}
+}
+
+func TestGitLabLabelQuery(t *testing.T) {
+	if got, want := gitlabLabelQuery(nil), ""; got != want {
+		t.Errorf("gitlabLabelQuery(nil) = %q, want %q", got, want)
+	}
+	if got, want := gitlabLabelQuery([]string{"bug", "p1"}), "labels=bug,p1"; got != want {
+		t.Errorf("gitlabLabelQuery() = %q, want %q", got, want)
+	}
+}
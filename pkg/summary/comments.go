@@ -0,0 +1,18 @@
+package summary
+
+import (
+	"regexp"
+
+	"github.com/sourcegraph/summary-issues/pkg/forge"
+)
+
+// lastMatchingComment returns the most recent comment in comments whose body
+// matches re, or nil if none do.
+func lastMatchingComment(comments []forge.Comment, re *regexp.Regexp) *forge.Comment {
+	for j := len(comments) - 1; j >= 0; j-- {
+		if re.MatchString(comments[j].Body) {
+			return &comments[j]
+		}
+	}
+	return nil
+}
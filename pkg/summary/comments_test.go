@@ -0,0 +1,44 @@
+package summary
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/sourcegraph/summary-issues/pkg/forge"
+)
+
+func TestLastMatchingComment(t *testing.T) {
+	comments := []forge.Comment{
+		{Author: "alice", Body: "working on it"},
+		{Author: "bob", Body: "SUMMARY: shipped v1"},
+		{Author: "alice", Body: "just chatting"},
+		{Author: "bob", Body: "SUMMARY: shipped v2"},
+	}
+	re := regexp.MustCompile(`^SUMMARY:`)
+
+	got := lastMatchingComment(comments, re)
+	if got == nil {
+		t.Fatal("lastMatchingComment() = nil, want a match")
+	}
+	if got.Body != "SUMMARY: shipped v2" {
+		t.Errorf("lastMatchingComment() = %q, want %q", got.Body, "SUMMARY: shipped v2")
+	}
+}
+
+func TestLastMatchingCommentNoMatch(t *testing.T) {
+	comments := []forge.Comment{
+		{Author: "alice", Body: "working on it"},
+	}
+	re := regexp.MustCompile(`^SUMMARY:`)
+
+	if got := lastMatchingComment(comments, re); got != nil {
+		t.Errorf("lastMatchingComment() = %#v, want nil", got)
+	}
+}
+
+func TestLastMatchingCommentEmpty(t *testing.T) {
+	re := regexp.MustCompile(`.*`)
+	if got := lastMatchingComment(nil, re); got != nil {
+		t.Errorf("lastMatchingComment(nil) = %#v, want nil", got)
+	}
+}
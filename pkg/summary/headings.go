@@ -0,0 +1,13 @@
+package summary
+
+import "regexp"
+
+var h1 = regexp.MustCompile(`(?m)^\s*#([^#]*)$`)
+var h2 = regexp.MustCompile(`(?m)^\s*##([^#]*)$`)
+
+// replaceHeadings replaces h1 and h2 headings with h3 headings so the summary issue formatting looks nice.
+func replaceHeadings(s string) string {
+	s = h2.ReplaceAllString(s, "###$1")
+	s = h1.ReplaceAllString(s, "###$1")
+	return s
+}
@@ -0,0 +1,49 @@
+package summary
+
+import "testing"
+
+func TestReplaceHeadings(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "h1 at start of line",
+			in:   "# Title",
+			want: "### Title",
+		},
+		{
+			name: "h2 at start of line",
+			in:   "## Subtitle",
+			want: "### Subtitle",
+		},
+		{
+			name: "leading whitespace before heading",
+			in:   "  # Indented",
+			want: "### Indented",
+		},
+		{
+			name: "h3 and beyond are left alone",
+			in:   "### Already fine",
+			want: "### Already fine",
+		},
+		{
+			name: "non-heading hash is untouched",
+			in:   "this is not a#heading",
+			want: "this is not a#heading",
+		},
+		{
+			name: "multiple headings across lines",
+			in:   "# One\ntext\n## Two\nmore text",
+			want: "### One\ntext\n### Two\nmore text",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := replaceHeadings(tt.in); got != tt.want {
+				t.Errorf("replaceHeadings(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package summary
+
+// Label is a single named label on an issue.
+type Label struct {
+	Name string `json:"name"`
+}
+
+// Labels is the set of labels on an issue.
+type Labels []Label
+
+// Contains reports whether name is one of l's labels.
+func (l Labels) Contains(name string) bool {
+	for _, n := range l {
+		if n.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// NonSummary returns l without the "summary" label itself, i.e. the labels
+// that define which issues should be rolled up.
+func (l Labels) NonSummary() Labels {
+	ls := Labels{}
+	for _, label := range l {
+		if label.Name != "summary" {
+			ls = append(ls, label)
+		}
+	}
+	return ls
+}
+
+// Names returns the non-summary label names, ready to pass to a
+// forge.Forge's search methods.
+func (l Labels) Names() []string {
+	ns := l.NonSummary()
+	names := make([]string, 0, len(ns))
+	for _, label := range ns {
+		names = append(names, label.Name)
+	}
+	return names
+}
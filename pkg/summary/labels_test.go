@@ -0,0 +1,52 @@
+package summary
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLabelsNames(t *testing.T) {
+	tests := []struct {
+		name   string
+		labels Labels
+		want   []string
+	}{
+		{
+			name:   "empty",
+			labels: Labels{},
+			want:   []string{},
+		},
+		{
+			name:   "filters out summary label",
+			labels: Labels{{Name: "summary"}, {Name: "team-a"}},
+			want:   []string{"team-a"},
+		},
+		{
+			name:   "preserves order of multiple labels",
+			labels: Labels{{Name: "bug"}, {Name: "summary"}, {Name: "p1"}},
+			want:   []string{"bug", "p1"},
+		},
+		{
+			name:   "only the summary label",
+			labels: Labels{{Name: "summary"}},
+			want:   []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.labels.Names(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Names() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLabelsContains(t *testing.T) {
+	labels := Labels{{Name: "summary"}, {Name: "team-a"}}
+	if !labels.Contains("summary") {
+		t.Errorf("Contains(%q) = false, want true", "summary")
+	}
+	if labels.Contains("team-b") {
+		t.Errorf("Contains(%q) = true, want false", "team-b")
+	}
+}
@@ -0,0 +1,161 @@
+package summary
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// digestMarker is the hidden HTML comment embedded in a summary issue's body
+// that records the state it was last digested from, so scheduled runs can
+// post only the delta instead of re-posting everything every time.
+var digestMarker = regexp.MustCompile(`<!-- summary-issues:digest:([A-Za-z0-9+/=]+) -->`)
+
+// digestIssueSnapshot is what a digestSnapshot remembers about one child
+// issue, enough to notice it changed or describe it after it's gone.
+type digestIssueSnapshot struct {
+	Title         string    `json:"title"`
+	URL           string    `json:"url"`
+	LastUpdatedAt time.Time `json:"lastUpdatedAt"`
+}
+
+// digestSnapshot is the state persisted in digestMarker between scheduled runs.
+type digestSnapshot struct {
+	At     time.Time                      `json:"at"`
+	Issues map[string]digestIssueSnapshot `json:"issues"`
+}
+
+// parseDigestSnapshot extracts the digestSnapshot embedded in body, if any.
+func parseDigestSnapshot(body string) *digestSnapshot {
+	m := digestMarker.FindStringSubmatch(body)
+	if m == nil {
+		return nil
+	}
+	data, err := base64.StdEncoding.DecodeString(m[1])
+	if err != nil {
+		return nil
+	}
+	var snap digestSnapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return nil
+	}
+	return &snap
+}
+
+// renderDigestMarker serializes snap into the hidden HTML comment stored on
+// the summary issue body.
+func renderDigestMarker(snap digestSnapshot) (string, error) {
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return "", fmt.Errorf("unable to marshal digest snapshot: %w", err)
+	}
+	return fmt.Sprintf("<!-- summary-issues:digest:%s -->", base64.StdEncoding.EncodeToString(data)), nil
+}
+
+// stripDigestMarker removes any existing digestMarker from body.
+func stripDigestMarker(body string) string {
+	return digestMarker.ReplaceAllString(body, "")
+}
+
+// diffDigest compares prev against curr and returns the child issues that
+// newly appeared, changed, or dropped out since the last digest.
+func diffDigest(prev, curr *digestSnapshot) (added, updated, removed []digestIssueSnapshot) {
+	for id, c := range curr.Issues {
+		p, ok := prev.Issues[id]
+		switch {
+		case !ok:
+			added = append(added, c)
+		case !c.LastUpdatedAt.Equal(p.LastUpdatedAt):
+			updated = append(updated, c)
+		}
+	}
+	for id, p := range prev.Issues {
+		if _, ok := curr.Issues[id]; !ok {
+			removed = append(removed, p)
+		}
+	}
+	return added, updated, removed
+}
+
+// renderDigestComment formats a delta-only comment body from diffDigest's output.
+func renderDigestComment(since time.Time, added, updated, removed []digestIssueSnapshot) string {
+	b := &strings.Builder{}
+	if len(added) > 0 {
+		fmt.Fprintf(b, "## Added since %s\n", since.Format("2006-01-02"))
+		for _, i := range added {
+			fmt.Fprintf(b, "- [%s](%s)\n", i.Title, i.URL)
+		}
+		b.WriteString("\n")
+	}
+	if len(updated) > 0 {
+		b.WriteString("## Updated\n")
+		for _, i := range updated {
+			fmt.Fprintf(b, "- [%s](%s)\n", i.Title, i.URL)
+		}
+		b.WriteString("\n")
+	}
+	if len(removed) > 0 {
+		b.WriteString("## Removed\n")
+		for _, i := range removed {
+			fmt.Fprintf(b, "- [%s](%s)\n", i.Title, i.URL)
+		}
+	}
+	return b.String()
+}
+
+// RunScheduledDigest implements the "schedule" event: for every open
+// label:summary issue, diff the currently-rolled-up child issues against the
+// snapshot from the last scheduled run and, if anything changed, post a new
+// dated comment with just the delta.
+func RunScheduledDigest(opts *Options) error {
+	issues, err := GetSummaryIssues(opts, Labels{})
+	if err != nil {
+		return err
+	}
+	for _, i := range issues {
+		si := Issue{ID: i.ID, URL: i.URL, Title: i.Title, Labels: toLabels(i.Labels)}
+		if err := PostScheduledDigest(opts, si, i.Body); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PostScheduledDigest handles one summary issue: it compares the current
+// rollup against the digest snapshot in previousBody, posts a delta comment
+// if anything changed, and persists a fresh snapshot either way.
+func PostScheduledDigest(opts *Options, si Issue, previousBody string) error {
+	fmt.Printf("digesting summary issue %q %s\n", si.Title, si.ID)
+
+	_, children, err := GenerateIssueSummary(opts, si)
+	if err != nil {
+		return err
+	}
+
+	curr := digestSnapshot{At: time.Now(), Issues: map[string]digestIssueSnapshot{}}
+	for _, c := range children {
+		lastUpdatedAt := c.CreatedAt
+		if cm := lastMatchingComment(c.Comments, opts.SummaryCommentRegex); cm != nil {
+			lastUpdatedAt = cm.UpdatedAt
+		}
+		curr.Issues[c.ID] = digestIssueSnapshot{Title: c.Title, URL: c.URL, LastUpdatedAt: lastUpdatedAt}
+	}
+
+	if prev := parseDigestSnapshot(previousBody); prev != nil {
+		added, updated, removed := diffDigest(prev, &curr)
+		if len(added)+len(updated)+len(removed) == 0 {
+			fmt.Printf("no changes since last digest for summary issue %q %s\n", si.Title, si.ID)
+		} else if err := opts.Forge.AddComment(si.ID, renderDigestComment(prev.At, added, updated, removed)); err != nil {
+			return err
+		}
+	}
+
+	marker, err := renderDigestMarker(curr)
+	if err != nil {
+		return err
+	}
+	return opts.Forge.UpdateIssueBody(si.ID, stripDigestMarker(previousBody)+"\n"+marker)
+}
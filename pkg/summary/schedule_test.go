@@ -0,0 +1,99 @@
+package summary
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDigestMarkerRoundTrip(t *testing.T) {
+	snap := digestSnapshot{
+		At: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+		Issues: map[string]digestIssueSnapshot{
+			"child-1": {Title: "Widgets are broken", URL: "https://example.com/1"},
+		},
+	}
+
+	marker, err := renderDigestMarker(snap)
+	if err != nil {
+		t.Fatalf("renderDigestMarker() error = %v", err)
+	}
+
+	body := "some summary text\n" + marker
+	got := parseDigestSnapshot(body)
+	if got == nil {
+		t.Fatal("parseDigestSnapshot() = nil, want a snapshot")
+	}
+	if !got.At.Equal(snap.At) {
+		t.Errorf("parseDigestSnapshot().At = %v, want %v", got.At, snap.At)
+	}
+	if got.Issues["child-1"].Title != "Widgets are broken" {
+		t.Errorf("parseDigestSnapshot().Issues = %+v", got.Issues)
+	}
+}
+
+func TestParseDigestSnapshotMissing(t *testing.T) {
+	if got := parseDigestSnapshot("no marker here"); got != nil {
+		t.Errorf("parseDigestSnapshot() = %+v, want nil", got)
+	}
+}
+
+func TestStripDigestMarker(t *testing.T) {
+	marker, err := renderDigestMarker(digestSnapshot{Issues: map[string]digestIssueSnapshot{}})
+	if err != nil {
+		t.Fatalf("renderDigestMarker() error = %v", err)
+	}
+
+	body := "some summary text\n" + marker
+	got := stripDigestMarker(body)
+	if got != "some summary text\n" {
+		t.Errorf("stripDigestMarker() = %q, want %q", got, "some summary text\n")
+	}
+}
+
+func TestDiffDigest(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	t1 := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	prev := &digestSnapshot{Issues: map[string]digestIssueSnapshot{
+		"unchanged": {Title: "unchanged", LastUpdatedAt: t0},
+		"changed":   {Title: "changed", LastUpdatedAt: t0},
+		"gone":      {Title: "gone", LastUpdatedAt: t0},
+	}}
+	curr := &digestSnapshot{Issues: map[string]digestIssueSnapshot{
+		"unchanged": {Title: "unchanged", LastUpdatedAt: t0},
+		"changed":   {Title: "changed", LastUpdatedAt: t1},
+		"new":       {Title: "new", LastUpdatedAt: t1},
+	}}
+
+	added, updated, removed := diffDigest(prev, curr)
+	if len(added) != 1 || added[0].Title != "new" {
+		t.Errorf("diffDigest() added = %+v, want [new]", added)
+	}
+	if len(updated) != 1 || updated[0].Title != "changed" {
+		t.Errorf("diffDigest() updated = %+v, want [changed]", updated)
+	}
+	if len(removed) != 1 || removed[0].Title != "gone" {
+		t.Errorf("diffDigest() removed = %+v, want [gone]", removed)
+	}
+}
+
+func TestRenderDigestComment(t *testing.T) {
+	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	added := []digestIssueSnapshot{{Title: "new issue", URL: "https://example.com/1"}}
+	updated := []digestIssueSnapshot{{Title: "updated issue", URL: "https://example.com/2"}}
+	removed := []digestIssueSnapshot{{Title: "removed issue", URL: "https://example.com/3"}}
+
+	got := renderDigestComment(since, added, updated, removed)
+	for _, want := range []string{"## Added since 2024-01-01", "new issue", "## Updated", "updated issue", "## Removed", "removed issue"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("renderDigestComment() = %q, missing %q", got, want)
+		}
+	}
+}
+
+func TestRenderDigestCommentEmpty(t *testing.T) {
+	if got := renderDigestComment(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), nil, nil, nil); got != "" {
+		t.Errorf("renderDigestComment() = %q, want empty", got)
+	}
+}
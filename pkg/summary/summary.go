@@ -0,0 +1,158 @@
+// Package summary contains the forge-agnostic logic for rolling up issues
+// into a summary issue: querying for matching issues, rendering the summary
+// body, and notifying child issues back. It knows nothing about GitHub,
+// GitLab, Gitea, or Gerrit specifically — all of that lives behind the
+// forge.Forge interface in package forge.
+package summary
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/sourcegraph/summary-issues/pkg/forge"
+)
+
+// Options configures how summary issues are found, queried, and rendered.
+type Options struct {
+	User                string
+	SummaryCommentRegex *regexp.Regexp
+	Forge               forge.Forge
+	TemplatePath        string
+	NotifyChildren      bool
+}
+
+// Issue is a summary issue: an issue carrying the "summary" label plus
+// whichever other labels define the set of child issues it rolls up.
+type Issue struct {
+	ID     string
+	URL    string
+	Title  string
+	Labels Labels
+}
+
+// UpdateSummaryIssues re-renders every summary issue whose label set
+// overlaps labels.
+func UpdateSummaryIssues(opts *Options, labels Labels) error {
+	issues, err := GetSummaryIssues(opts, labels)
+	if err != nil {
+		return err
+	}
+	for _, i := range issues {
+		if err := UpdateSummaryIssue(opts, Issue{
+			ID:     i.ID,
+			URL:    i.URL,
+			Title:  i.Title,
+			Labels: toLabels(i.Labels),
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetSummaryIssues finds the open summary issues whose labels overlap labels.
+func GetSummaryIssues(opts *Options, labels Labels) ([]*forge.Issue, error) {
+	names := labels.Names()
+	fmt.Printf("searching summary issues with labels %v\n", names)
+	return opts.Forge.SearchSummaryIssues(opts.User, names)
+}
+
+// UpdateSummaryIssue re-renders si's body from its current child issues and,
+// if configured, notifies those children back.
+func UpdateSummaryIssue(opts *Options, si Issue) error {
+	fmt.Printf("updating summary issue %q %s\n", si.Title, si.ID)
+
+	body, children, err := GenerateIssueSummary(opts, si)
+	if err != nil {
+		return err
+	}
+
+	if err := opts.Forge.UpdateIssueBody(si.ID, body); err != nil {
+		return err
+	}
+
+	if opts.NotifyChildren {
+		if err := NotifyChildren(opts, si, children); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GenerateIssueSummary renders si's summary body and returns the child
+// issues that were rolled up into it, so callers can notify them back (see
+// NotifyChildren) without re-querying the forge.
+func GenerateIssueSummary(opts *Options, si Issue) (string, []*forge.Issue, error) {
+	issues, err := SummarizedIssues(opts, si)
+	if err != nil {
+		return "", nil, err
+	}
+
+	issuesWithMatchingLabels := "issues with matching labels"
+	if u := opts.Forge.LabeledIssuesURL(opts.User, si.Labels.Names()); u != "" {
+		issuesWithMatchingLabels = fmt.Sprintf("[%s](%s)", issuesWithMatchingLabels, u)
+	}
+
+	data := summaryTemplateData{
+		IssuesLink:          issuesWithMatchingLabels,
+		SummaryCommentRegex: opts.SummaryCommentRegex.String(),
+	}
+	var children []*forge.Issue
+	for _, i := range issues {
+		if i.ID == si.ID {
+			continue
+		}
+		data.Issues = append(data.Issues, toSummarizedIssue(i, opts.SummaryCommentRegex))
+		children = append(children, i)
+	}
+
+	tmpl, err := loadSummaryTemplate(opts.TemplatePath)
+	if err != nil {
+		return "", nil, err
+	}
+
+	body := &strings.Builder{}
+	if err := tmpl.Execute(body, data); err != nil {
+		return "", nil, fmt.Errorf("error executing summary template: %w", err)
+	}
+	return body.String(), children, nil
+}
+
+// SummarizedIssues finds the issues matching si's (non-summary) labels.
+func SummarizedIssues(opts *Options, si Issue) ([]*forge.Issue, error) {
+	return opts.Forge.SearchLabeledIssues(opts.User, si.Labels.Names())
+}
+
+// NotifyChildren posts or edits a single bot comment on each child issue
+// rolled up into si's summary, linking back to it. The comment is tracked
+// via a hidden marker so repeated runs edit the same comment instead of
+// piling up duplicates.
+func NotifyChildren(opts *Options, si Issue, children []*forge.Issue) error {
+	marker := SummaryMarker(si.ID)
+	for _, c := range children {
+		body := fmt.Sprintf("%s\nIncluded in summary [%s](%s) on %s.", marker, si.Title, si.URL, time.Now().Format("2006-01-02"))
+		if err := opts.Forge.UpsertComment(c.ID, marker, body); err != nil {
+			return fmt.Errorf("unable to notify child issue %s: %w", c.ID, err)
+		}
+	}
+	return nil
+}
+
+// SummaryMarker returns the hidden HTML marker used to find the bot comment
+// NotifyChildren should edit across runs, rather than creating a new one
+// each time.
+func SummaryMarker(summaryID string) string {
+	return fmt.Sprintf("<!-- summary-issues:bot:%s -->", summaryID)
+}
+
+// toLabels converts the plain label names a Forge returns into the Labels
+// type the rest of the package works with.
+func toLabels(names []string) Labels {
+	ls := make(Labels, 0, len(names))
+	for _, n := range names {
+		ls = append(ls, Label{Name: n})
+	}
+	return ls
+}
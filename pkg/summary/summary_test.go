@@ -0,0 +1,101 @@
+package summary
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/summary-issues/pkg/forge"
+)
+
+// fakeForge is an in-memory forge.Forge used to test the summary package's
+// orchestration without hitting a real code host.
+type fakeForge struct {
+	issues   map[string]*forge.Issue
+	comments map[string][]forge.Comment
+}
+
+func newFakeForge(issues ...*forge.Issue) *fakeForge {
+	f := &fakeForge{issues: map[string]*forge.Issue{}, comments: map[string][]forge.Comment{}}
+	for _, i := range issues {
+		f.issues[i.ID] = i
+	}
+	return f
+}
+
+func (f *fakeForge) SearchSummaryIssues(user string, labelNames []string) ([]*forge.Issue, error) {
+	return nil, nil
+}
+
+func (f *fakeForge) SearchLabeledIssues(user string, labelNames []string) ([]*forge.Issue, error) {
+	return nil, nil
+}
+
+func (f *fakeForge) LabeledIssuesURL(user string, labelNames []string) string {
+	return ""
+}
+
+func (f *fakeForge) UpdateIssueBody(id, body string) error {
+	f.issues[id].Body = body
+	return nil
+}
+
+func (f *fakeForge) UpsertComment(id, marker, body string) error {
+	for i, c := range f.comments[id] {
+		if strings.Contains(c.Body, marker) {
+			f.comments[id][i].Body = body
+			return nil
+		}
+	}
+	f.comments[id] = append(f.comments[id], forge.Comment{Body: body})
+	return nil
+}
+
+func (f *fakeForge) AddComment(id, body string) error {
+	f.comments[id] = append(f.comments[id], forge.Comment{Body: body})
+	return nil
+}
+
+func TestNotifyChildrenCreatesOneCommentPerChild(t *testing.T) {
+	f := newFakeForge()
+	opts := &Options{Forge: f}
+	si := Issue{ID: "summary-1", Title: "Q1 rollup", URL: "https://example.com/summary-1"}
+	children := []*forge.Issue{
+		{ID: "child-1"},
+		{ID: "child-2"},
+	}
+
+	if err := NotifyChildren(opts, si, children); err != nil {
+		t.Fatalf("NotifyChildren() error = %v", err)
+	}
+
+	if len(f.comments["child-1"]) != 1 {
+		t.Errorf("comments on child-1 = %+v, want 1", f.comments["child-1"])
+	}
+	if len(f.comments["child-2"]) != 1 {
+		t.Errorf("comments on child-2 = %+v, want 1", f.comments["child-2"])
+	}
+}
+
+func TestNotifyChildrenEditsExistingComment(t *testing.T) {
+	f := newFakeForge()
+	opts := &Options{Forge: f}
+	si := Issue{ID: "summary-1", Title: "Q1 rollup", URL: "https://example.com/summary-1"}
+	children := []*forge.Issue{{ID: "child-1"}}
+
+	if err := NotifyChildren(opts, si, children); err != nil {
+		t.Fatalf("NotifyChildren() error = %v", err)
+	}
+	if err := NotifyChildren(opts, si, children); err != nil {
+		t.Fatalf("NotifyChildren() error = %v", err)
+	}
+
+	if len(f.comments["child-1"]) != 1 {
+		t.Errorf("comments on child-1 = %+v, want 1 (should edit, not duplicate)", f.comments["child-1"])
+	}
+}
+
+func TestSummaryMarker(t *testing.T) {
+	if got, want := SummaryMarker("summary-1"), "<!-- summary-issues:bot:summary-1 -->"; got != want {
+		t.Errorf("SummaryMarker() = %q, want %q", got, want)
+	}
+}
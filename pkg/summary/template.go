@@ -0,0 +1,134 @@
+package summary
+
+import (
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/sourcegraph/summary-issues/pkg/forge"
+)
+
+// SummarizedIssue is the per-issue view handed to the summary template.
+type SummarizedIssue struct {
+	Title         string
+	URL           string
+	Author        string
+	Labels        []string
+	LastComment   string
+	StatusClass   string
+	SubmittedAt   time.Time
+	LastUpdatedAt time.Time
+	LastUpdatedBy string
+}
+
+// summaryTemplateData is the top-level value passed to the summary template.
+type summaryTemplateData struct {
+	IssuesLink          string
+	SummaryCommentRegex string
+	Issues              []SummarizedIssue
+}
+
+// classifyStatus inspects an issue's state and its latest matching comment
+// to tag it with a coarse status class a template can group or style by.
+// "merged" and "abandoned" are Gerrit-only distinctions; GitHub, GitLab, and
+// Gitea issues only ever report "closed".
+func classifyStatus(i *forge.Issue, re *regexp.Regexp) string {
+	switch strings.ToLower(i.State) {
+	case "merged":
+		return "merged"
+	case "closed":
+		return "closed"
+	case "abandoned":
+		return "abandoned"
+	}
+
+	c := lastMatchingComment(i.Comments, re)
+	if c == nil || c.Author == i.Author {
+		return "needs-response"
+	}
+	return "open"
+}
+
+// toSummarizedIssue projects a forge.Issue and its latest matching comment
+// into the shape the summary template renders.
+func toSummarizedIssue(i *forge.Issue, re *regexp.Regexp) SummarizedIssue {
+	si := SummarizedIssue{
+		Title:         i.Title,
+		URL:           i.URL,
+		Author:        i.Author,
+		Labels:        i.Labels,
+		StatusClass:   classifyStatus(i, re),
+		SubmittedAt:   i.CreatedAt,
+		LastUpdatedAt: i.CreatedAt,
+		LastUpdatedBy: i.Author,
+	}
+
+	if c := lastMatchingComment(i.Comments, re); c != nil {
+		si.LastComment = replaceHeadings(c.Body)
+		si.LastUpdatedAt = c.UpdatedAt
+		si.LastUpdatedBy = c.Author
+	} else {
+		si.LastComment = "_No update_"
+	}
+	return si
+}
+
+// groupByLabel buckets issues by each of their labels; an issue with several
+// labels appears in each bucket.
+func groupByLabel(issues []SummarizedIssue) map[string][]SummarizedIssue {
+	groups := map[string][]SummarizedIssue{}
+	for _, i := range issues {
+		for _, l := range i.Labels {
+			groups[l] = append(groups[l], i)
+		}
+	}
+	return groups
+}
+
+// groupByStatus buckets issues by their classifyStatus class.
+func groupByStatus(issues []SummarizedIssue) map[string][]SummarizedIssue {
+	groups := map[string][]SummarizedIssue{}
+	for _, i := range issues {
+		groups[i.StatusClass] = append(groups[i.StatusClass], i)
+	}
+	return groups
+}
+
+var templateFuncs = template.FuncMap{
+	"groupByLabel":    groupByLabel,
+	"groupByStatus":   groupByStatus,
+	"replaceHeadings": replaceHeadings,
+}
+
+// defaultSummaryTemplateText follows the layout of the tool's original
+// fixed-format output and is used when --template isn't passed. Unlike that
+// output, an issue with no matching comment also gets an "_Updated_" line,
+// showing the issue's creation time and author.
+const defaultSummaryTemplateText = `{{if .SummaryCommentRegex}}_This is generated from the newest comment that matches the regular expression ` + "`{{.SummaryCommentRegex}}`" + ` on {{.IssuesLink}}._
+{{else}}_This is generated from the newest comment on {{.IssuesLink}}._
+{{end}}{{if not .Issues}}
+No matching issues.
+{{else}}{{range .Issues}}
+## [{{.Title}}]({{.URL}})
+{{.LastComment}}
+
+_Updated {{.LastUpdatedAt.Format "2006-01-02 15:04:05 MST"}} by @{{.LastUpdatedBy}}_
+
+{{end}}{{end}}`
+
+// loadSummaryTemplate parses the summary template at path, or the built-in
+// default if path is empty.
+func loadSummaryTemplate(path string) (*template.Template, error) {
+	text := defaultSummaryTemplateText
+	if path != "" {
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read summary template %s: %w", path, err)
+		}
+		text = string(b)
+	}
+	return template.New("summary").Funcs(templateFuncs).Parse(text)
+}
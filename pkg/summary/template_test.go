@@ -0,0 +1,135 @@
+package summary
+
+import (
+	"io/ioutil"
+	"regexp"
+	"testing"
+
+	"github.com/sourcegraph/summary-issues/pkg/forge"
+)
+
+func TestClassifyStatus(t *testing.T) {
+	re := regexp.MustCompile(`^SUMMARY:`)
+	tests := []struct {
+		name string
+		i    *forge.Issue
+		want string
+	}{
+		{
+			name: "merged",
+			i:    &forge.Issue{State: "MERGED"},
+			want: "merged",
+		},
+		{
+			name: "closed",
+			i:    &forge.Issue{State: "CLOSED"},
+			want: "closed",
+		},
+		{
+			name: "abandoned",
+			i:    &forge.Issue{State: "abandoned"},
+			want: "abandoned",
+		},
+		{
+			name: "no matching comment needs a response",
+			i:    &forge.Issue{State: "open", Author: "alice"},
+			want: "needs-response",
+		},
+		{
+			name: "latest matching comment is from the author themselves",
+			i: &forge.Issue{
+				State:  "open",
+				Author: "alice",
+				Comments: []forge.Comment{
+					{Author: "alice", Body: "SUMMARY: still working on it"},
+				},
+			},
+			want: "needs-response",
+		},
+		{
+			name: "latest matching comment is from someone else",
+			i: &forge.Issue{
+				State:  "open",
+				Author: "alice",
+				Comments: []forge.Comment{
+					{Author: "bob", Body: "SUMMARY: looks good to me"},
+				},
+			},
+			want: "open",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classifyStatus(tt.i, re); got != tt.want {
+				t.Errorf("classifyStatus() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGroupByLabel(t *testing.T) {
+	issues := []SummarizedIssue{
+		{Title: "a", Labels: []string{"bug", "team-a"}},
+		{Title: "b", Labels: []string{"team-a"}},
+		{Title: "c", Labels: []string{"bug"}},
+	}
+
+	groups := groupByLabel(issues)
+	if len(groups["bug"]) != 2 {
+		t.Errorf("groupByLabel()[bug] = %+v, want 2 issues", groups["bug"])
+	}
+	if len(groups["team-a"]) != 2 {
+		t.Errorf("groupByLabel()[team-a] = %+v, want 2 issues", groups["team-a"])
+	}
+	if _, ok := groups["missing"]; ok {
+		t.Errorf("groupByLabel() has unexpected key %q", "missing")
+	}
+}
+
+func TestGroupByStatus(t *testing.T) {
+	issues := []SummarizedIssue{
+		{Title: "a", StatusClass: "open"},
+		{Title: "b", StatusClass: "merged"},
+		{Title: "c", StatusClass: "open"},
+	}
+
+	groups := groupByStatus(issues)
+	if len(groups["open"]) != 2 {
+		t.Errorf("groupByStatus()[open] = %+v, want 2 issues", groups["open"])
+	}
+	if len(groups["merged"]) != 1 {
+		t.Errorf("groupByStatus()[merged] = %+v, want 1 issue", groups["merged"])
+	}
+}
+
+func TestLoadSummaryTemplateDefault(t *testing.T) {
+	tmpl, err := loadSummaryTemplate("")
+	if err != nil {
+		t.Fatalf("loadSummaryTemplate(\"\") error = %v", err)
+	}
+	if tmpl == nil {
+		t.Fatal("loadSummaryTemplate(\"\") returned nil template")
+	}
+}
+
+func TestLoadSummaryTemplateCustomPath(t *testing.T) {
+	dir := t.TempDir()
+	path := dir + "/custom.tmpl"
+	if err := ioutil.WriteFile(path, []byte("{{range .Issues}}{{.Title}}\n{{end}}"), 0o644); err != nil {
+		t.Fatalf("ioutil.WriteFile() error = %v", err)
+	}
+
+	tmpl, err := loadSummaryTemplate(path)
+	if err != nil {
+		t.Fatalf("loadSummaryTemplate(%q) error = %v", path, err)
+	}
+	if tmpl == nil {
+		t.Fatal("loadSummaryTemplate() returned nil template")
+	}
+}
+
+func TestLoadSummaryTemplateMissingFile(t *testing.T) {
+	if _, err := loadSummaryTemplate("/nonexistent/path.tmpl"); err == nil {
+		t.Error("loadSummaryTemplate(missing path) error = nil, want non-nil")
+	}
+}